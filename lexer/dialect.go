@@ -0,0 +1,134 @@
+package lexer
+
+import "strings"
+
+// Keyword describes a single keyword recognized by a Dialect.
+type Keyword struct {
+	// Reserved reports whether the keyword is reserved, meaning it cannot
+	// be used as an unquoted identifier in this dialect.
+	Reserved bool
+}
+
+// Dialect is a set of SQL keywords, and their reserved-vs-non-reserved
+// status, that a Lexer consults while classifying bare words. Use one of
+// the built-in dialects (DialectANSI, DialectMySQL, DialectPostgreSQL,
+// DialectSQLite) or build a custom one with NewDialect.
+type Dialect struct {
+	Name     string
+	keywords map[string]Keyword
+}
+
+// statementStartKeywords mark the first token of a new SQL statement. They
+// are emitted as ItemStatementStart instead of ItemKeyword regardless of
+// dialect, so callers can track statement boundaries without a second pass
+// over the token stream.
+var statementStartKeywords = map[string]bool{
+	"SELECT": true,
+	"INSERT": true,
+	"UPDATE": true,
+	"DELETE": true,
+	"CREATE": true,
+	"DROP":   true,
+}
+
+// NewDialect builds a Dialect from explicit reserved and non-reserved
+// keyword lists. Keyword lookups are case-insensitive, so callers may pass
+// words in any case.
+func NewDialect(name string, reserved, nonReserved []string) Dialect {
+	keywords := make(map[string]Keyword, len(reserved)+len(nonReserved))
+	for _, w := range reserved {
+		keywords[strings.ToUpper(w)] = Keyword{Reserved: true}
+	}
+	for _, w := range nonReserved {
+		keywords[strings.ToUpper(w)] = Keyword{Reserved: false}
+	}
+	return Dialect{Name: name, keywords: keywords}
+}
+
+// Lookup reports whether word is a keyword in d, along with its Keyword
+// info. The match is case-insensitive, as SQL keywords are.
+func (d Dialect) Lookup(word string) (Keyword, bool) {
+	kw, ok := d.keywords[strings.ToUpper(word)]
+	return kw, ok
+}
+
+// IsKeyword reports whether word is a keyword (reserved or not) in d.
+func (d Dialect) IsKeyword(word string) bool {
+	_, ok := d.Lookup(word)
+	return ok
+}
+
+// IsReserved reports whether word is a reserved keyword in d.
+func (d Dialect) IsReserved(word string) bool {
+	kw, ok := d.Lookup(word)
+	return ok && kw.Reserved
+}
+
+// IsStatementStart reports whether word begins a new statement.
+func (d Dialect) IsStatementStart(word string) bool {
+	return statementStartKeywords[strings.ToUpper(word)]
+}
+
+// ansiReservedKeywords are reserved in standard SQL and in DialectMySQL and
+// DialectPostgreSQL; DialectSQLite keeps only a few of them reserved (see
+// sqliteDowngraded).
+var ansiReservedKeywords = []string{
+	"SELECT", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER",
+	"FROM", "WHERE", "INTO", "VALUES", "SET",
+	"AND", "OR", "NOT", "NULL", "AS", "IS", "IN", "BETWEEN", "LIKE", "EXISTS",
+	"JOIN", "INNER", "OUTER", "LEFT", "RIGHT", "FULL", "CROSS", "ON",
+	"GROUP", "BY", "ORDER", "HAVING", "LIMIT", "OFFSET", "DISTINCT", "ALL", "UNION",
+	"TABLE", "INDEX", "VIEW", "PRIMARY", "FOREIGN", "KEY", "REFERENCES",
+	"DEFAULT", "UNIQUE", "CHECK", "CONSTRAINT", "COLUMN",
+	"CASE", "WHEN", "THEN", "ELSE", "END", "BEGIN",
+	"ASC", "DESC", "WITH",
+}
+
+// ansiNonReservedKeywords may be used as unquoted identifiers in standard SQL.
+var ansiNonReservedKeywords = []string{
+	"TYPE", "DATA", "LEVEL", "NAME",
+}
+
+// DialectANSI is the standards-conformant SQL dialect, used as the default
+// when no dialect is specified.
+var DialectANSI = NewDialect("ANSI SQL", ansiReservedKeywords, ansiNonReservedKeywords)
+
+// DialectMySQL adds MySQL-specific keywords on top of the ANSI base.
+var DialectMySQL = NewDialect("MySQL", append(append([]string{}, ansiReservedKeywords...),
+	"AUTO_INCREMENT", "REPLACE", "IGNORE", "ENGINE", "CHARSET", "COLLATE",
+	"UNSIGNED", "ZEROFILL", "LOCK", "UNLOCK",
+), append(append([]string{}, ansiNonReservedKeywords...),
+	"STATUS", "VARIABLES",
+))
+
+// DialectPostgreSQL adds PostgreSQL-specific keywords on top of the ANSI base.
+var DialectPostgreSQL = NewDialect("PostgreSQL", append(append([]string{}, ansiReservedKeywords...),
+	"RETURNING", "ILIKE", "ARRAY", "SERIAL", "ONLY", "USING", "LATERAL",
+), append(append([]string{}, ansiNonReservedKeywords...),
+	"SEQUENCE", "SCHEMA",
+))
+
+// DialectSQLite adds SQLite-specific keywords. SQLite treats almost all of
+// its keywords as non-reserved, so most of the ANSI reserved set is
+// downgraded here - SQLite allows them as identifiers in most contexts.
+var DialectSQLite = NewDialect("SQLite",
+	[]string{"SELECT", "INSERT", "UPDATE", "DELETE", "FROM", "WHERE"},
+	append(append(append([]string{}, ansiNonReservedKeywords...), sqliteDowngraded()...),
+		"AUTOINCREMENT", "PRAGMA", "WITHOUT", "ROWID", "VACUUM", "ATTACH", "DETACH", "IF",
+	),
+)
+
+// sqliteDowngraded returns the ANSI reserved keywords that SQLite, unlike
+// other dialects, treats as non-reserved.
+func sqliteDowngraded() []string {
+	downgraded := make([]string, 0, len(ansiReservedKeywords))
+	for _, w := range ansiReservedKeywords {
+		switch w {
+		case "SELECT", "INSERT", "UPDATE", "DELETE", "FROM", "WHERE":
+			continue
+		default:
+			downgraded = append(downgraded, w)
+		}
+	}
+	return downgraded
+}
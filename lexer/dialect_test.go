@@ -0,0 +1,72 @@
+package lexer
+
+import "testing"
+
+func TestDialectLookup(t *testing.T) {
+	tests := []struct {
+		name         string
+		dialect      Dialect
+		word         string
+		wantKeyword  bool
+		wantReserved bool
+	}{
+		{name: "ANSI reserved keyword", dialect: DialectANSI, word: "SELECT", wantKeyword: true, wantReserved: true},
+		{name: "ANSI non-reserved keyword", dialect: DialectANSI, word: "TYPE", wantKeyword: true, wantReserved: false},
+		{name: "not a keyword", dialect: DialectANSI, word: "widgets", wantKeyword: false, wantReserved: false},
+		{name: "lookup is case-insensitive", dialect: DialectANSI, word: "select", wantKeyword: true, wantReserved: true},
+		{name: "MySQL-specific keyword", dialect: DialectMySQL, word: "AUTO_INCREMENT", wantKeyword: true, wantReserved: true},
+		{name: "MySQL doesn't know a PostgreSQL-only keyword", dialect: DialectMySQL, word: "ILIKE", wantKeyword: false},
+		{name: "PostgreSQL-specific keyword", dialect: DialectPostgreSQL, word: "RETURNING", wantKeyword: true, wantReserved: true},
+		{name: "PostgreSQL doesn't know a MySQL-only keyword", dialect: DialectPostgreSQL, word: "AUTO_INCREMENT", wantKeyword: false},
+		{name: "SQLite downgrades a normally-reserved ANSI keyword", dialect: DialectSQLite, word: "GROUP", wantKeyword: true, wantReserved: false},
+		{name: "SQLite keeps SELECT reserved", dialect: DialectSQLite, word: "SELECT", wantKeyword: true, wantReserved: true},
+		{name: "SQLite-specific keyword", dialect: DialectSQLite, word: "PRAGMA", wantKeyword: true, wantReserved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.IsKeyword(tt.word); got != tt.wantKeyword {
+				t.Errorf("IsKeyword(%q) = %v, want %v", tt.word, got, tt.wantKeyword)
+			}
+			if got := tt.dialect.IsReserved(tt.word); got != tt.wantReserved {
+				t.Errorf("IsReserved(%q) = %v, want %v", tt.word, got, tt.wantReserved)
+			}
+		})
+	}
+}
+
+func TestDialectIsStatementStart(t *testing.T) {
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"SELECT", true},
+		{"insert", true},
+		{"DROP", true},
+		{"WHERE", false},
+		{"widgets", false},
+	}
+
+	for _, tt := range tests {
+		if got := DialectANSI.IsStatementStart(tt.word); got != tt.want {
+			t.Errorf("IsStatementStart(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestNewDialect(t *testing.T) {
+	d := NewDialect("custom", []string{"FOO"}, []string{"bar"})
+
+	if !d.IsReserved("foo") {
+		t.Error(`IsReserved("foo") = false, want true`)
+	}
+	if !d.IsKeyword("BAR") {
+		t.Error(`IsKeyword("BAR") = false, want true`)
+	}
+	if d.IsReserved("BAR") {
+		t.Error(`IsReserved("BAR") = true, want false`)
+	}
+	if d.IsKeyword("baz") {
+		t.Error(`IsKeyword("baz") = true, want false`)
+	}
+}
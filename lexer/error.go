@@ -0,0 +1,37 @@
+package lexer
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// LexError describes a failure encountered while scanning the input. It is
+// the structured counterpart to the message carried in an ItemError's Val.
+type LexError struct {
+	Pos     int    // the byte offset the error occurred at
+	Line    int    // 1-based line number
+	Column  int    // 1-based column (in runes)
+	State   string // name of the state function active when the error occurred
+	Snippet string // the offending rune, or "EOF" if input ended unexpectedly
+	Msg     string // human-readable description
+}
+
+func (e *LexError) Error() string {
+	return fmt.Sprintf("%d:%d: %s (in %s, near %q)", e.Line, e.Column, e.Msg, e.State, e.Snippet)
+}
+
+// stateName returns the unqualified function name of a StateFn, for
+// inclusion in a LexError. Returns "" for a nil StateFn.
+func stateName(fn StateFn) string {
+	if fn == nil {
+		return ""
+	}
+
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -2,52 +2,43 @@ package lexer
 
 import (
 	"fmt"
-	"testing"
+	"strings"
 )
 
-func ExampleInsertLexing() {
+func ExampleLex() {
 	itemNames := map[ItemType]string{
 		ItemError:          "error",
 		ItemEOF:            "EOF",
 		ItemKeyword:        "keyword",
+		ItemStatementStart: "statement_start",
 		ItemOperator:       "operator",
 		ItemIdentifier:     "identifier",
 		ItemLeftParen:      "left_paren",
-		ItemNumber:         "number",
 		ItemRightParen:     "right_paren",
-		ItemSpace:          "space",
+		ItemNumber:         "number",
 		ItemString:         "string",
-		ItemComment:        "comment",
-		ItemStatementStart: "statement_start",
 		ItemStetementEnd:   "statement_end",
 	}
 
-	ppItem = func(i Item) string {
-		return fmt.Sprintf("%q('%q')", itemNames[i.t], t.val)
-	}
-
 	query := "SELECT * FROM `users` WHERE id = 15;"
+	l := Lex(strings.NewReader(query), WithDialect(DialectMySQL))
 
-	lexer := lex("testlexer", query)
-
-	for {
-		item, ok := <-lexer.Items
-		if !ok {
-			break
+	for item := range l.Items() {
+		if item.Type == ItemWhitespace {
+			continue
 		}
-		fmt.Println(ppItem(item))
+		fmt.Printf("%s(%q)\n", itemNames[item.Type], item.Val)
 	}
 
 	// Output:
-	// statement_start('')
-	// keyword('SELECT')
-	// identifier('*')
-	// keyword('FROM')
-	// identifier('`users`')
-	// keyword('WHERE')
-	// identifier('id')
-	// operator('=')
-	// number('15')
-	// statement_start(';')
-	// EOF('')
+	// statement_start("SELECT")
+	// operator("*")
+	// keyword("FROM")
+	// identifier("`users`")
+	// keyword("WHERE")
+	// identifier("id")
+	// operator("=")
+	// number("15")
+	// statement_end(";")
+	// EOF("")
 }
@@ -0,0 +1,32 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWildcardAfterDot guards against a regression where the character
+// following a qualified identifier's dot - if it wasn't itself the start of
+// another identifier - fell through to the trailing whitespace-emit in
+// lexIdentifierOrKeyword and was mislabeled ItemWhitespace instead of its
+// own token.
+func TestWildcardAfterDot(t *testing.T) {
+	l := Lex(strings.NewReader("t.*"))
+
+	want := []struct {
+		typ ItemType
+		val string
+	}{
+		{ItemIdentifier, "t"},
+		{ItemDot, "."},
+		{ItemOperator, "*"},
+		{ItemEOF, ""},
+	}
+
+	for i, w := range want {
+		item := l.NextItem()
+		if item.Type != w.typ || item.Val != w.val {
+			t.Fatalf("item %d = %v(%q), want %v(%q)", i, item.Type, item.Val, w.typ, w.val)
+		}
+	}
+}
@@ -1,102 +1,682 @@
-
-package Lexer
+package lexer
 
 import (
-	"io"
+	"bufio"
 	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
+//TODO: support other encodings besides utf-8 (conversion before the lexer?)
+
 // ItemType identifies the type of lex Items.
 type ItemType int
 
 // Item represents a token or text string returned from the scanner.
 type Item struct {
-	typ ItemType // The type of this Item.
-	val string   // The value of this Item.
+	Type   ItemType  // The type of this Item.
+	Pos    int       // The starting position, in bytes, of this item in the input string.
+	Line   int       // The 1-based line number this item starts on.
+	Column int       // The 1-based column (in runes) this item starts at.
+	Val    string    // The value of this Item.
+	Param  ParamKind // The kind of parameter this Item represents, when Type is ItemParameter.
 }
 
 const (
-	ItemError        ItemType = iota // error occurred; value is text of error
-	ItemEOF
-	ItemIdentifier // alphanumeric identifier not starting with '.'
-	ItemLeftParen  // '('
-	ItemNumber     // simple number, including imaginary
-	ItemRightParen // ')'
-	ItemSpace      // run of spaces separating arguments
-	ItemString     // quoted string (includes quotes)
-	ItemComment    // comments
-	ItemStatementStart // start of a statement like SELECT
-	ItemStetementEnd // ';'
-	// etc.
+	ItemError             ItemType = iota // error occurred; value is text of error
+	ItemEOF                               // end of the file
+	ItemWhitespace                        // a run of spaces, tabs and newlines
+	ItemSingleLineComment                 // A comment like --
+	ItemMultiLineComment                  // A multiline comment like /* ... */
+	ItemKeyword                           // SQL language keyword like SELECT, INSERT, etc.
+	ItemStatementStart                    // a keyword that begins a new statement, like SELECT or INSERT
+	ItemIdentifier                        // alphanumeric identifier or complex identifier like `a.b` and `c`.*
+	ItemOperator                          // operators like '=', '<>', etc.
+	ItemConcatOperator                    // '||' when ModePipesAsConcat is set
+	ItemLeftParen                         // '('
+	ItemRightParen                        // ')'
+	ItemComma                             // ','
+	ItemDot                               // '.'
+	ItemStetementEnd                      // ';'
+	ItemNumber                            // simple number, including imaginary
+	ItemString                            // quoted string (includes quotes)
+	ItemNationalString                    // national character string, e.g. N'...'
+	ItemDollarString                      // PostgreSQL dollar-quoted string, e.g. $tag$...$tag$
+	ItemBitString                         // bit string literal, e.g. B'0101'
+	ItemHexString                         // hex string literal, e.g. X'1A2B'
+	ItemParameter                         // bind parameter/placeholder; see Item.Param for its kind
 )
 
+const EOF = -1
+
 // StateFn represents the state of the scanner as a function that returns the next state.
 type StateFn func(*Lexer) StateFn
 
+// ValidatorFn represents a function that is used to check whether a specific rune matches certain rules.
+type ValidatorFn func(rune) bool
+
 // Lexer holds the state of the scanner.
 type Lexer struct {
-	name       string    // the name of the input; used only for error reports
-	//TODO: maybe use a chan of runes?
-	input      Reader    // the input source
-	state      StateFn   // the next lexing function to enter
-	//TODO: some way to remember current position, start of Item and last read witdh
-	Items      chan Item // channel of scanned Items
-	parenDepth int       // nesting depth of ( ) exprs
+	state             StateFn       // the next lexing function to enter, or nil once the scan has ended
+	input             io.RuneReader // the input source
+	inputCurrentStart int           // start position of this item, in bytes
+	line              int           // 1-based line number of the start of this item
+	col               int           // 1-based column (in runes) of the start of this item
+	buffer            []rune        // a slice of runes that contains the currently lexed item
+	bufferPos         int           // the current position in the buffer
+	queue             []Item        // Items produced by the current state step, awaiting NextItem
+	dialect           Dialect       // the SQL dialect used to classify keywords
+	mode              Mode          // SQL mode flags controlling escapes and identifier quoting
+	lastErr           *LexError     // the most recent lex error, if any
+}
+
+// Option configures a Lexer. Pass one or more Options to Lex.
+type Option func(*Lexer)
+
+// WithDialect sets the SQL dialect the Lexer uses to recognize keywords.
+// If omitted, Lex defaults to DialectANSI.
+func WithDialect(d Dialect) Option {
+	return func(l *Lexer) {
+		l.dialect = d
+	}
 }
 
-// next returns the next rune in the input.
+// next() returns the next rune in the input.
 func (l *Lexer) next() rune {
-	//TODO: implement
+	if l.bufferPos < len(l.buffer) {
+		res := l.buffer[l.bufferPos]
+		l.bufferPos++
+		return res
+	}
+
+	r, _, err := l.input.ReadRune()
+	if err == io.EOF {
+		r = EOF
+	} else if err != nil {
+		panic(err)
+	}
+
+	l.buffer = append(l.buffer, r)
+	l.bufferPos++
+	return r
 }
 
-// peek returns but does not consume the next rune in the input.
+// peek() returns but does not consume the next rune in the input.
 func (l *Lexer) peek() rune {
-	//TODO: implement
+	if l.bufferPos < len(l.buffer) {
+		return l.buffer[l.bufferPos]
+	}
+
+	r, _, err := l.input.ReadRune()
+	if err == io.EOF {
+		r = EOF
+	} else if err != nil {
+		panic(err)
+	}
+
+	l.buffer = append(l.buffer, r)
+	return r
+}
+
+// peek() returns but does not consume the next few runes in the input.
+func (l *Lexer) peekNext(length int) string {
+	lenDiff := l.bufferPos + length - len(l.buffer)
+	if lenDiff > 0 {
+		for i := 0; i < lenDiff; i++ {
+			r, _, err := l.input.ReadRune()
+			if err == io.EOF {
+				r = EOF
+			} else if err != nil {
+				panic(err)
+			}
+
+			l.buffer = append(l.buffer, r)
+		}
+	}
+
+	return string(l.buffer[l.bufferPos : l.bufferPos+length])
 }
 
-// backup steps back one rune. Can only be called once per call of next.
+// backup steps back one rune
 func (l *Lexer) backup() {
-	//TODO: implement
+	l.backupWith(1)
 }
 
-// emit passes an Item back to the client.
+// backup steps back many runes
+func (l *Lexer) backupWith(length int) {
+	if l.bufferPos < length {
+		panic(fmt.Errorf("lexer: trying to backup with %d when the buffer position is %d", length, l.bufferPos))
+	}
+
+	l.bufferPos -= length
+}
+
+// emit queues an Item for the client to receive from NextItem.
 func (l *Lexer) emit(t ItemType) {
-	//TODO: implement
+	l.queue = append(l.queue, Item{
+		Type:   t,
+		Pos:    l.inputCurrentStart,
+		Line:   l.line,
+		Column: l.col,
+		Val:    string(l.buffer[:l.bufferPos]),
+	})
+	l.ignore()
 }
 
-// ignore skips over the pending input before this point.
+// ignore skips over the pending input before this point, advancing the
+// byte offset and the line/column counters over the runes it covers.
 func (l *Lexer) ignore() {
-	//TODO: implement
+	itemByteLen := 0
+	for i := 0; i < l.bufferPos; i++ {
+		r := l.buffer[i]
+		itemByteLen += utf8.RuneLen(r)
+
+		switch {
+		case r == '\n':
+			l.line++
+			l.col = 1
+		case r == '\r':
+			// Treat \r\n as a single line break; a lone \r (old Mac style)
+			// still counts as one.
+			if i+1 >= l.bufferPos || l.buffer[i+1] != '\n' {
+				l.line++
+				l.col = 1
+			}
+		default:
+			l.col++
+		}
+	}
+
+	l.inputCurrentStart += itemByteLen
+	l.buffer = l.buffer[l.bufferPos:] //TODO: check for memory leaks, maybe copy remaining items into a new slice?
+	l.bufferPos = 0
 }
 
 // accept consumes the next rune if it's from the valid set.
-func (l *Lexer) accept(valid string) bool {
-	//TODO: implement
+func (l *Lexer) accept(valid string) int {
+	r := l.next()
+	if strings.IndexRune(valid, r) >= 0 {
+		return 1
+	}
+	l.backup()
+	return 0
+}
+
+// acceptWhile consumes runes while the specified condition is true
+func (l *Lexer) acceptWhile(fn ValidatorFn) int {
+	r := l.next()
+	count := 0
+	for fn(r) {
+		r = l.next()
+		count++
+	}
+	l.backup()
+	return count
+}
+
+// acceptUntil consumes runes until the specified contidtion is met
+func (l *Lexer) acceptUntil(fn ValidatorFn) int {
+	r := l.next()
+	count := 0
+	for !fn(r) && r != EOF {
+		r = l.next()
+		count++
+	}
+	l.backup()
+	return count
+}
+
+// errorf records a structured *LexError, queues a matching error Item and
+// terminates the scan by returning a nil StateFn, which becomes the next
+// state, ending the lex.
+func (l *Lexer) errorf(format string, args ...interface{}) StateFn {
+	snippet := "EOF"
+	if r := l.peek(); r != EOF {
+		snippet = string(r)
+	}
+
+	err := &LexError{
+		Pos:     l.inputCurrentStart,
+		Line:    l.line,
+		Column:  l.col,
+		State:   stateName(l.state),
+		Snippet: snippet,
+		Msg:     fmt.Sprintf(format, args...),
+	}
+	l.lastErr = err
+
+	l.queue = append(l.queue, Item{
+		Type:   ItemError,
+		Pos:    l.inputCurrentStart,
+		Line:   l.line,
+		Column: l.col,
+		Val:    err.Error(),
+	})
+	return nil
+}
+
+// Err returns the structured error from the most recent errorf call, or nil
+// if the lex has not produced an ItemError.
+func (l *Lexer) Err() *LexError {
+	return l.lastErr
+}
+
+// NextItem returns the next Item from the input, driving the state machine
+// synchronously: it runs states until one of them queues an Item, then
+// returns that Item. Once the scan has ended (EOF or an error was
+// returned), NextItem keeps returning an ItemEOF Item.
+func (l *Lexer) NextItem() Item {
+	for len(l.queue) == 0 {
+		if l.state == nil {
+			return Item{Type: ItemEOF, Pos: l.inputCurrentStart, Line: l.line, Column: l.col}
+		}
+		l.state = l.state(l)
+	}
+
+	item := l.queue[0]
+	l.queue = l.queue[1:]
+	return item
+}
+
+// Items adapts NextItem to a channel, for callers that prefer to range over
+// a channel of Items rather than pull them one at a time. It spawns a
+// goroutine that forwards NextItem results until EOF or an error, then
+// closes the returned channel.
+func (l *Lexer) Items() <-chan Item {
+	items := make(chan Item)
+	go func() {
+		defer close(items)
+		for {
+			item := l.NextItem()
+			items <- item
+			if item.Type == ItemEOF || item.Type == ItemError {
+				return
+			}
+		}
+	}()
+	return items
+}
+
+// Lex creates a new scanner for the input, applying any Options given.
+// It defaults to DialectANSI when no WithDialect option is supplied.
+func Lex(input io.Reader, opts ...Option) *Lexer {
+	l := &Lexer{
+		input:   bufio.NewReader(input),
+		buffer:  make([]rune, 0, 10),
+		state:   lexWhitespace,
+		line:    1,
+		col:     1,
+		dialect: DialectANSI,
+		mode:    defaultMode,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// isSpace reports whether r is a whitespace character (space or end of line).
+func isWhitespace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n'
+}
+
+// isSpace reports whether r is a space character.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// isEndOfLine reports whether r is an end-of-line character.
+func isEndOfLine(r rune) bool {
+	return r == '\r' || r == '\n' || r == EOF
+}
+
+// isAlphaNumeric reports whether r is an alphabetic, digit, or underscore.
+func isAlphaNumeric(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isOperator reports whether r is an operator.
+func isOperator(r rune) bool {
+	return r == '+' || r == '-' || r == '*' || r == '/' || r == '=' || r == '>' || r == '<' || r == '~' || r == '|' || r == '^' || r == '&' || r == '%'
+}
+
+func lexWhitespace(l *Lexer) StateFn {
+	l.acceptWhile(isWhitespace)
+	if l.bufferPos > 0 {
+		l.emit(ItemWhitespace)
+	}
+
+	next := l.peek()
+	nextTwo := l.peekNext(2)
+
+	switch {
+	case next == EOF:
+		l.emit(ItemEOF)
+		return nil
+
+	case nextTwo == "--":
+		return lexSingleLineComment
+
+	case nextTwo == "/*":
+		return lexMultiLineComment
+
+	case next == '(':
+		l.next()
+		l.emit(ItemLeftParen)
+		return lexWhitespace
+
+	case next == ')':
+		l.next()
+		l.emit(ItemRightParen)
+		return lexWhitespace
+
+	case next == ',':
+		l.next()
+		l.emit(ItemComma)
+		return lexWhitespace
+
+	case next == ';':
+		l.next()
+		l.emit(ItemStetementEnd)
+		return lexWhitespace
+
+	case isOperator(next):
+		return lexOperator
+
+	case next == '"' && l.mode&ModeANSIQuotes != 0:
+		return lexIdentifierOrKeyword
+
+	case next == '"' || next == '\'':
+		return lexString
+
+	case next == '?' || next == '@':
+		return lexParameter
+
+	case next == '$' && isNumberedParamStart(nextTwo):
+		return lexParameter
+
+	case next == '$':
+		return lexDollarString
+
+	case next == ':' && isNamedParamStart(nextTwo):
+		return lexParameter
+
+	case isPrefixedStringStart(next, nextTwo):
+		return lexPrefixedString
+
+	case ('0' <= next && next <= '9'):
+		return lexNumber
+
+	case isAlphaNumeric(next) || (next == '`' && l.mode&ModeBacktickIdentifiers != 0):
+		return lexIdentifierOrKeyword
+
+	default:
+		l.errorf("don't know what to do with '%s'", nextTwo)
+		return nil
+	}
+}
+
+// isPrefixedStringStart reports whether next begins one of the single-letter
+// string prefixes (E'...' escape strings, B'...' bit strings, X'...' hex
+// strings, N'...' national strings) - that is, next is one of those letters
+// and it's immediately followed by a quote, with no space between.
+func isPrefixedStringStart(next rune, nextTwo string) bool {
+	runes := []rune(nextTwo)
+	if len(runes) < 2 || runes[1] != '\'' {
+		return false
+	}
+
+	switch next {
+	case 'E', 'e', 'B', 'b', 'X', 'x', 'N', 'n':
+		return true
+	default:
+		return false
+	}
+}
+
+// lexPrefixedString dispatches to the state for whichever single-letter
+// string prefix is present; isPrefixedStringStart has already confirmed one
+// of E/B/X/N is immediately followed by a quote.
+func lexPrefixedString(l *Lexer) StateFn {
+	prefix := l.next()
+
+	switch prefix {
+	case 'B', 'b':
+		return lexQuotedDigits(l, ItemBitString, func(r rune) bool { return r == '0' || r == '1' })
+	case 'X', 'x':
+		return lexQuotedDigits(l, ItemHexString, isHexDigit)
+	case 'N', 'n':
+		return lexQuotedString(l, ItemNationalString)
+	default: // 'E', 'e'
+		return lexQuotedString(l, ItemString)
+	}
+}
+
+// lexQuotedDigits scans a 'digits' literal like B'0101' or X'1A2B': a quote,
+// a run of runes accepted by valid, and a closing quote. No escaping.
+func lexQuotedDigits(l *Lexer, item ItemType, valid ValidatorFn) StateFn {
+	quote := l.next()
+	l.acceptWhile(valid)
+	if l.next() != quote {
+		l.backup() // leave the offending rune for errorf's snippet
+		return l.errorf("unterminated literal")
+	}
+
+	l.emit(item)
+	return lexWhitespace
+}
+
+// isHexDigit reports whether r is a valid hexadecimal digit.
+func isHexDigit(r rune) bool {
+	return ('0' <= r && r <= '9') || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F')
+}
+
+// lexDollarString scans a PostgreSQL dollar-quoted string: $tag$ ... $tag$,
+// where tag is an optional identifier. Unlike lexQuotedString, nothing
+// inside is escaped - the string simply runs until the matching $tag$.
+func lexDollarString(l *Lexer) StateFn {
+	l.next() // opening '$'
+	l.acceptWhile(isAlphaNumeric)
+	if l.next() != '$' {
+		l.backup() // leave the offending rune for errorf's snippet
+		return l.errorf("malformed dollar-quoted string tag")
+	}
+
+	// "tag$", what must follow a '$' to close the string; copied since
+	// l.buffer keeps growing (and may be reallocated) as we scan ahead.
+	closer := append([]rune{}, l.buffer[1:l.bufferPos]...)
+	closerStr := string(closer)
+
+	for {
+		l.acceptUntil(func(r rune) bool { return r == '$' })
+		if l.peek() == EOF {
+			return l.errorf("unterminated dollar-quoted string")
+		}
+
+		l.next() // consume the '$'
+		if l.peekNext(len(closer)) == closerStr {
+			for range closer {
+				l.next()
+			}
+			l.emit(ItemDollarString)
+			return lexWhitespace
+		}
+	}
+}
+
+func lexSingleLineComment(l *Lexer) StateFn {
+	l.acceptUntil(isEndOfLine)
+	l.emit(ItemSingleLineComment)
+	return lexWhitespace
+}
+
+func lexMultiLineComment(l *Lexer) StateFn {
+	l.next()
+	l.next()
+	for {
+		l.acceptUntil(func(r rune) bool { return r == '*' })
+		if l.peekNext(2) == "*/" {
+			l.next()
+			l.next()
+			l.emit(ItemMultiLineComment)
+			return lexWhitespace
+		}
+
+		if l.peek() == EOF {
+			l.errorf("reached EOF when looking for comment end")
+			return nil
+		}
+
+		l.next()
+	}
 }
 
-// acceptRun consumes a run of runes from the valid set.
-func (l *Lexer) acceptRun(valid string) {
-	//TODO: implement
+func lexOperator(l *Lexer) StateFn {
+	l.acceptWhile(isOperator)
+	text := string(l.buffer[:l.bufferPos])
+
+	if text == "||" && l.mode&ModePipesAsConcat != 0 {
+		l.emit(ItemConcatOperator)
+	} else {
+		l.emit(ItemOperator)
+	}
+	return lexWhitespace
+}
+
+func lexNumber(l *Lexer) StateFn {
+	count := 0
+	count += l.acceptWhile(unicode.IsDigit)
+	if l.accept(".") > 0 {
+		count += 1 + l.acceptWhile(unicode.IsDigit)
+	}
+	if l.accept("eE") > 0 {
+		count += 1 + l.accept("+-")
+		count += l.acceptWhile(unicode.IsDigit)
+	}
+
+	if isAlphaNumeric(l.peek()) {
+		// We were lexing an identifier all along - backup and pass the ball
+		l.backupWith(count)
+		return lexIdentifierOrKeyword
+	}
+
+	l.emit(ItemNumber)
+	return lexWhitespace
 }
 
-// nextItem returns the next Item from the input.
-func (l *Lexer) nextItem() Item {
-	//TODO: implement
+func lexString(l *Lexer) StateFn {
+	return lexQuotedString(l, ItemString)
 }
 
-// lex creates a new scanner for the input string.
-func lex(name, input, left, right string) *Lexer {
-	//TODO: implement
+// lexQuotedString scans a '...' or "..." style string, honoring backslash
+// escapes (unless ModeNoBackslashEscapes is set) and doubled-quote
+// escaping, and emits it as item. It backs
+// lexString as well as the N'...' and E'...' prefixed forms, which only
+// differ in the ItemType they're tagged with.
+func lexQuotedString(l *Lexer, item ItemType) StateFn {
+	quote := l.next()
+
+	for {
+		n := l.next()
+
+		if n == EOF {
+			return l.errorf("unterminated quoted string")
+		}
+		if n == '\\' && l.mode&ModeNoBackslashEscapes == 0 {
+			if l.peek() == EOF {
+				return l.errorf("unterminated quoted string")
+			}
+			l.next()
+		}
+
+		if n == quote {
+			if l.peek() == quote {
+				l.next()
+			} else {
+				l.emit(item)
+				return lexWhitespace
+			}
+		}
+	}
+
 }
 
-// run runs the state machine for the Lexer.
-func (l *Lexer) run() {
-	//TODO: implement
+// scanQuotedIdentifier consumes a quote-delimited identifier segment up to
+// its closing quote (doubled to escape, e.g. ``a``b``), and emits it as an
+// ItemIdentifier. The opening quote has already been consumed by the
+// caller. It reports ok=false, having already called errorf, if the
+// identifier is unterminated.
+func (l *Lexer) scanQuotedIdentifier(quote rune) (ok bool) {
+	for {
+		n := l.next()
+
+		if n == EOF {
+			l.errorf("unterminated quoted identifier")
+			return false
+		} else if n == quote {
+			if l.peek() == quote {
+				l.next()
+			} else {
+				break
+			}
+		}
+	}
+
+	l.emit(ItemIdentifier)
+	return true
 }
 
+func lexIdentifierOrKeyword(l *Lexer) StateFn {
+	for {
+		s := l.next()
 
-//TODO: different state functions that correspond to different ItemTypes like:
-// func lexComment(l *Lexer) StateFn {...}
-// func lexSpace(l *Lexer) StateFn {...}
-// func lexIdentifier(l *Lexer) StateFn {...}
-// etc.
\ No newline at end of file
+		switch {
+		case s == '`' && l.mode&ModeBacktickIdentifiers != 0:
+			if !l.scanQuotedIdentifier('`') {
+				return nil
+			}
+		case s == '"' && l.mode&ModeANSIQuotes != 0:
+			if !l.scanQuotedIdentifier('"') {
+				return nil
+			}
+		case isAlphaNumeric(s):
+			l.acceptWhile(isAlphaNumeric)
+			word := string(l.buffer[:l.bufferPos])
+
+			switch {
+			case l.dialect.IsStatementStart(word):
+				l.emit(ItemStatementStart)
+			case l.dialect.IsKeyword(word):
+				l.emit(ItemKeyword)
+			default:
+				l.emit(ItemIdentifier)
+			}
+
+		default:
+			// Not a continuation of the identifier (e.g. the '*' of a
+			// trailing wildcard in "t.*"); back up and let lexWhitespace
+			// dispatch it as its own token instead of falling through to
+			// the whitespace-emit below, which would otherwise swallow it
+			// into a bogus ItemWhitespace.
+			l.backup()
+		}
+
+		l.acceptWhile(isWhitespace)
+		if l.bufferPos > 0 {
+			l.emit(ItemWhitespace)
+		}
+
+		if l.peek() != '.' {
+			break
+		}
+
+		l.next()
+		l.emit(ItemDot)
+	}
+
+	return lexWhitespace
+}
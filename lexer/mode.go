@@ -0,0 +1,39 @@
+package lexer
+
+// Mode is a bitmask of SQL-mode flags that adjust escape handling and
+// identifier quoting. Combine flags with |, e.g.
+// WithMode(ModeANSIQuotes | ModeNoBackslashEscapes).
+type Mode uint
+
+const (
+	// ModeNoBackslashEscapes disables backslash escaping inside '...' and
+	// "..." strings, so a backslash is an ordinary character (standard SQL,
+	// and MySQL's NO_BACKSLASH_ESCAPES mode).
+	ModeNoBackslashEscapes Mode = 1 << iota
+
+	// ModeANSIQuotes makes "..." a quoted identifier rather than a string
+	// literal, as in standard SQL.
+	ModeANSIQuotes
+
+	// ModePipesAsConcat marks a bare || operator as ItemConcatOperator
+	// rather than ItemOperator, matching PostgreSQL (and MySQL's
+	// PIPES_AS_CONCAT mode) where || is string concatenation rather than
+	// logical OR.
+	ModePipesAsConcat
+
+	// ModeBacktickIdentifiers treats `...` as a quoted identifier, as MySQL
+	// does. It is on by default.
+	ModeBacktickIdentifiers
+)
+
+// defaultMode is applied when Lex is called without a WithMode option.
+const defaultMode = ModeBacktickIdentifiers
+
+// WithMode sets the SQL mode flags the Lexer uses for escape handling and
+// identifier quoting. It replaces the default mode entirely, so combine
+// every flag you need into a single call.
+func WithMode(m Mode) Option {
+	return func(l *Lexer) {
+		l.mode = m
+	}
+}
@@ -0,0 +1,81 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModeFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		mode     Mode
+		useMode  bool // false relies on the Lex default (defaultMode)
+		wantType ItemType
+	}{
+		{
+			name:     "backslash escapes a quote by default",
+			input:    `'it\'s'`,
+			wantType: ItemString,
+		},
+		{
+			name:     "ModeNoBackslashEscapes treats backslash as an ordinary character",
+			input:    `'it\'`,
+			mode:     ModeNoBackslashEscapes,
+			useMode:  true,
+			wantType: ItemString,
+		},
+		{
+			name:     "double quotes are strings by default",
+			input:    `"id"`,
+			wantType: ItemString,
+		},
+		{
+			name:     "ModeANSIQuotes makes double quotes a quoted identifier",
+			input:    `"id"`,
+			mode:     ModeANSIQuotes,
+			useMode:  true,
+			wantType: ItemIdentifier,
+		},
+		{
+			name:     "|| is a plain operator by default",
+			input:    "||",
+			wantType: ItemOperator,
+		},
+		{
+			name:     "ModePipesAsConcat marks || as concatenation",
+			input:    "||",
+			mode:     ModePipesAsConcat,
+			useMode:  true,
+			wantType: ItemConcatOperator,
+		},
+		{
+			name:     "ModeBacktickIdentifiers is on by default",
+			input:    "`id`",
+			wantType: ItemIdentifier,
+		},
+		{
+			name:     "backticks are rejected without ModeBacktickIdentifiers",
+			input:    "`id`",
+			mode:     ModePipesAsConcat, // any mode value that omits the flag
+			useMode:  true,
+			wantType: ItemError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.useMode {
+				opts = append(opts, WithMode(tt.mode))
+			}
+
+			l := Lex(strings.NewReader(tt.input), opts...)
+			item := l.NextItem()
+
+			if item.Type != tt.wantType {
+				t.Fatalf("Type = %v, want %v (val %q, err %v)", item.Type, tt.wantType, item.Val, l.Err())
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package lexer
+
+import "unicode"
+
+// ParamKind classifies the flavor of a bind parameter/placeholder Item.
+type ParamKind int
+
+const (
+	ParamPositional      ParamKind = iota // '?' (JDBC/MySQL positional)
+	ParamNumbered                         // '$1', '$2', ... (PostgreSQL numbered)
+	ParamNamed                            // ':name'
+	ParamSessionVariable                  // '@name' or '@@name'
+)
+
+// isNumberedParamStart reports whether nextTwo - the next two runes of
+// input - begins a PostgreSQL-style numbered parameter like $1: a '$'
+// immediately followed by a digit. A '$' followed by anything else is the
+// start of a dollar-quoted string instead.
+func isNumberedParamStart(nextTwo string) bool {
+	runes := []rune(nextTwo)
+	return len(runes) > 1 && unicode.IsDigit(runes[1])
+}
+
+// isNamedParamStart reports whether nextTwo begins a named parameter like
+// :name - a ':' immediately followed by an identifier character.
+func isNamedParamStart(nextTwo string) bool {
+	runes := []rune(nextTwo)
+	return len(runes) > 1 && isAlphaNumeric(runes[1])
+}
+
+// emitParam is like emit, but also records which flavor of parameter the
+// queued Item represents.
+func (l *Lexer) emitParam(kind ParamKind) {
+	l.queue = append(l.queue, Item{
+		Type:   ItemParameter,
+		Pos:    l.inputCurrentStart,
+		Line:   l.line,
+		Column: l.col,
+		Val:    string(l.buffer[:l.bufferPos]),
+		Param:  kind,
+	})
+	l.ignore()
+}
+
+// lexParameter scans a single bind parameter token: '?', '$1'/'$2', ':name'
+// or '@name'/'@@name'.
+func lexParameter(l *Lexer) StateFn {
+	switch l.peek() {
+	case '?':
+		l.next()
+		l.emitParam(ParamPositional)
+
+	case '$':
+		l.next()
+		l.acceptWhile(unicode.IsDigit)
+		l.emitParam(ParamNumbered)
+
+	case ':':
+		l.next()
+		l.acceptWhile(isAlphaNumeric)
+		l.emitParam(ParamNamed)
+
+	case '@':
+		l.next()
+		l.accept("@") // optional second '@' for session (as opposed to user) variables
+		l.acceptWhile(isAlphaNumeric)
+		l.emitParam(ParamSessionVariable)
+	}
+
+	return lexWhitespace
+}
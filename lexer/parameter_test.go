@@ -0,0 +1,38 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParameterKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantKind ParamKind
+	}{
+		{name: "positional", input: "?", wantKind: ParamPositional},
+		{name: "numbered", input: "$1", wantKind: ParamNumbered},
+		{name: "numbered multi-digit", input: "$12", wantKind: ParamNumbered},
+		{name: "named", input: ":name", wantKind: ParamNamed},
+		{name: "session variable", input: "@name", wantKind: ParamSessionVariable},
+		{name: "session variable, double at", input: "@@global_var", wantKind: ParamSessionVariable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Lex(strings.NewReader(tt.input))
+			item := l.NextItem()
+
+			if item.Type != ItemParameter {
+				t.Fatalf("Type = %v, want ItemParameter (err %v)", item.Type, l.Err())
+			}
+			if item.Val != tt.input {
+				t.Errorf("Val = %q, want %q", item.Val, tt.input)
+			}
+			if item.Param != tt.wantKind {
+				t.Errorf("Param = %v, want %v", item.Param, tt.wantKind)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestItemPositions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Item // Type, Val, Pos, Line, Column only
+	}{
+		{
+			name:  "single line",
+			input: "SELECT id",
+			want: []Item{
+				{Type: ItemStatementStart, Val: "SELECT", Pos: 0, Line: 1, Column: 1},
+				{Type: ItemIdentifier, Val: "id", Pos: 7, Line: 1, Column: 8},
+			},
+		},
+		{
+			name:  "tracks line and column across newlines",
+			input: "SELECT\nid",
+			want: []Item{
+				{Type: ItemStatementStart, Val: "SELECT", Pos: 0, Line: 1, Column: 1},
+				{Type: ItemIdentifier, Val: "id", Pos: 7, Line: 2, Column: 1},
+			},
+		},
+		{
+			name:  "treats \\r\\n as a single line break",
+			input: "SELECT\r\nid",
+			want: []Item{
+				{Type: ItemStatementStart, Val: "SELECT", Pos: 0, Line: 1, Column: 1},
+				{Type: ItemIdentifier, Val: "id", Pos: 8, Line: 2, Column: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Lex(strings.NewReader(tt.input))
+
+			var got []Item
+			for {
+				item := l.NextItem()
+				if item.Type == ItemEOF {
+					break
+				}
+				if item.Type == ItemWhitespace {
+					continue
+				}
+				got = append(got, Item{Type: item.Type, Val: item.Val, Pos: item.Pos, Line: item.Line, Column: item.Column})
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d items %+v, want %d %+v", len(got), got, len(tt.want), tt.want)
+			}
+			for i, item := range got {
+				if item != tt.want[i] {
+					t.Errorf("item %d = %+v, want %+v", i, item, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLexErrorPosition(t *testing.T) {
+	l := Lex(strings.NewReader("SELECT #"))
+
+	var item Item
+	for {
+		item = l.NextItem()
+		if item.Type == ItemError || item.Type == ItemEOF {
+			break
+		}
+	}
+
+	if item.Type != ItemError {
+		t.Fatalf("got item type %v, want ItemError", item.Type)
+	}
+
+	err := l.Err()
+	if err == nil {
+		t.Fatal("Err() returned nil after an ItemError")
+	}
+	if err.Line != 1 || err.Column != 8 {
+		t.Errorf("LexError position = %d:%d, want 1:8", err.Line, err.Column)
+	}
+	if err.Snippet != "#" {
+		t.Errorf("LexError.Snippet = %q, want %q", err.Snippet, "#")
+	}
+	if err.State != "lexWhitespace" {
+		t.Errorf("LexError.State = %q, want %q", err.State, "lexWhitespace")
+	}
+}
@@ -0,0 +1,43 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStringLiteralForms(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantType ItemType
+	}{
+		{name: "single quoted", input: "'hello'", wantType: ItemString},
+		{name: "double quoted", input: `"hello"`, wantType: ItemString},
+		{name: "dollar-quoted with tag", input: "$tag$hello; world$tag$", wantType: ItemDollarString},
+		{name: "dollar-quoted with empty tag", input: "$$hello$$", wantType: ItemDollarString},
+		{name: "escape string", input: `E'it''s\n'`, wantType: ItemString},
+		{name: "bit string", input: "B'0101'", wantType: ItemBitString},
+		{name: "hex string", input: "X'1A2B'", wantType: ItemHexString},
+		{name: "national string", input: "N'héllo'", wantType: ItemNationalString},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Lex(strings.NewReader(tt.input))
+			item := l.NextItem()
+
+			if item.Type != tt.wantType {
+				t.Fatalf("Type = %v, want %v (err: %v)", item.Type, tt.wantType, l.Err())
+			}
+			// The literal is the whole input, so the emitted value should
+			// round-trip it exactly, quotes/tag/prefix included.
+			if item.Val != tt.input {
+				t.Errorf("Val = %q, want %q", item.Val, tt.input)
+			}
+
+			if end := l.NextItem(); end.Type != ItemEOF {
+				t.Errorf("expected EOF after the literal, got %v(%q)", end.Type, end.Val)
+			}
+		})
+	}
+}
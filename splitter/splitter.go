@@ -0,0 +1,407 @@
+// Package splitter builds on lexer to split a stream of SQL text into
+// individual statements, the way a tool that feeds statements one at a
+// time to database/sql (or redacts/logs them one at a time) needs.
+package splitter
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/sh4t/sql-parser/lexer"
+)
+
+// Statement is a single SQL statement extracted from a stream by
+// SplitStatements.
+type Statement struct {
+	Text   string       // the statement's source text, delimiter included
+	Items  []lexer.Item // the statement's tokens, lexed without the delimiter
+	Line   int          // 1-based line the statement starts on
+	Column int          // 1-based column (in bytes) the statement starts at
+}
+
+const defaultDelimiter = ";"
+
+// SplitStatements reads whole SQL statements out of r, one at a time. It
+// tracks quotes, comments, parenthesized expressions, and BEGIN/CASE ...
+// END nesting, so that none of those split a statement early, and it
+// recognizes the MySQL client's `DELIMITER <token>` directive, so dump
+// files that redefine the terminator around stored-routine bodies (BEGIN
+// ... END blocks, function bodies, CREATE PROCEDURE) split the way the
+// mysql client would.
+//
+// opts are forwarded to lexer.Lex when tokenizing each statement.
+func SplitStatements(r io.Reader, opts ...lexer.Option) iter.Seq[Statement] {
+	return func(yield func(Statement) bool) {
+		s := &splitState{r: bufio.NewReader(r), delimiter: defaultDelimiter, line: 1, col: 1}
+
+		for {
+			stmt, more := s.scanStatement(opts)
+			if stmt != nil && !yield(*stmt) {
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+// splitState is a lightweight, string/comment-aware scanner used only to
+// find statement boundaries. It tracks just enough syntax to tell a
+// delimiter occurrence that ends a statement from one embedded in its
+// body; once a statement's text is isolated, it's handed to lexer.Lex for
+// real tokenization.
+type splitState struct {
+	r         *bufio.Reader
+	delimiter string
+	line, col int
+}
+
+func (s *splitState) readByte() (byte, bool) {
+	b, err := s.r.ReadByte()
+	if err != nil {
+		return 0, false
+	}
+	if b == '\n' {
+		s.line++
+		s.col = 1
+	} else {
+		s.col++
+	}
+	return b, true
+}
+
+func (s *splitState) mustReadByte() byte {
+	b, _ := s.readByte()
+	return b
+}
+
+// peekByte returns the next byte without consuming it, or 0 at EOF.
+func (s *splitState) peekByte() byte {
+	b, err := s.r.Peek(1)
+	if err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (s *splitState) skipLeadingSpace() {
+	for isSpaceByte(s.peekByte()) {
+		s.readByte()
+	}
+}
+
+// tryDelimiterDirective recognizes a `DELIMITER <token>` line (case
+// insensitive), consuming it entirely, including the trailing newline.
+// The caller must already be positioned past any leading whitespace.
+func (s *splitState) tryDelimiterDirective() (string, bool) {
+	const kw = "delimiter"
+
+	peeked, _ := s.r.Peek(len(kw) + 1)
+	if len(peeked) <= len(kw) || strings.ToLower(string(peeked[:len(kw)])) != kw || !isSpaceByte(peeked[len(kw)]) {
+		return "", false
+	}
+
+	for range len(kw) {
+		s.readByte()
+	}
+	s.skipLeadingSpace()
+
+	var tok []byte
+	for {
+		b := s.peekByte()
+		if b == 0 || isSpaceByte(b) {
+			break
+		}
+		tok = append(tok, s.mustReadByte())
+	}
+	if len(tok) == 0 {
+		return "", false
+	}
+
+	// Consume the rest of the directive's line.
+	for {
+		b := s.peekByte()
+		if b == 0 || b == '\n' {
+			break
+		}
+		s.readByte()
+	}
+	if s.peekByte() == '\n' {
+		s.readByte()
+	}
+
+	return string(tok), true
+}
+
+// peekEndContinuation reports whether the upcoming input, after skipping
+// any whitespace, spells IF, WHILE, or LOOP as a whole word - the
+// continuation that turns a bare END into END IF/END WHILE/END LOOP,
+// closing a construct whose opener never bumped blockDepth. The caller has
+// already consumed the whitespace byte that ended the END word and only
+// asks when there was one. It does not consume any input.
+func (s *splitState) peekEndContinuation() bool {
+	const maxContinuation = "WHILE" // the longest of IF/WHILE/LOOP
+
+	peeked, _ := s.r.Peek(1 + len(maxContinuation) + 1)
+
+	i := 0
+	for i < len(peeked) && isSpaceByte(peeked[i]) {
+		i++
+	}
+
+	rest := peeked[i:]
+	for _, kw := range []string{"IF", "WHILE", "LOOP"} {
+		if len(rest) < len(kw) || !strings.EqualFold(string(rest[:len(kw)]), kw) {
+			continue
+		}
+		if len(rest) == len(kw) || !isWordByte(rest[len(kw)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// scanStatement reads up to and including the next statement boundary. It
+// returns the Statement found (nil if only trailing whitespace remained)
+// and whether there may be more input to scan.
+func (s *splitState) scanStatement(opts []lexer.Option) (*Statement, bool) {
+	var buf, word []byte
+	parenDepth, blockDepth := 0, 0
+	started := false
+	var startLine, startCol int
+
+	skipContinuation := false
+
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		if skipContinuation {
+			// The continuation keyword of an "END IF"/"END WHILE"/"END
+			// LOOP" just flushed by itself, as a separate word; it was
+			// already accounted for (as a no-op) when the preceding END
+			// was flushed, so it must not be double-counted here.
+			skipContinuation = false
+			word = word[:0]
+			return
+		}
+		switch strings.ToUpper(string(word)) {
+		case "BEGIN", "CASE":
+			blockDepth++
+		case "END":
+			// buf's last byte is the one that ended the word; END IF etc.
+			// need whitespace there.
+			if len(buf) > 0 && isSpaceByte(buf[len(buf)-1]) && s.peekEndContinuation() {
+				// "END IF"/"END WHILE"/"END LOOP" closes a construct
+				// whose opener (IF/WHILE/LOOP) never bumped blockDepth,
+				// so the pair is a no-op rather than a real decrement.
+				skipContinuation = true
+			} else if blockDepth > 0 {
+				blockDepth--
+			}
+		}
+		word = word[:0]
+	}
+
+	for {
+		if !started {
+			s.skipLeadingSpace()
+			if newDelim, ok := s.tryDelimiterDirective(); ok {
+				s.delimiter = newDelim
+				continue
+			}
+			startLine, startCol = s.line, s.col
+		}
+
+		b, ok := s.readByte()
+		if !ok {
+			flushWord()
+			text := strings.TrimSpace(string(buf))
+			if text == "" {
+				return nil, false
+			}
+			return &Statement{Text: text, Items: s.lex(text, opts), Line: startLine, Column: startCol}, false
+		}
+		started = true
+
+		buf = append(buf, b)
+
+		switch {
+		case b == '\'' || b == '"' || b == '`':
+			flushWord()
+			s.skipQuoted(&buf, b)
+
+		case b == '-' && s.peekByte() == '-':
+			flushWord()
+			buf = append(buf, s.mustReadByte())
+			s.skipLineComment(&buf)
+			// The comment's bytes (which may themselves contain the
+			// delimiter, e.g. "-- foo;") were appended to buf above; don't
+			// let the suffix check below mistake them for a real terminator.
+			continue
+
+		case b == '/' && s.peekByte() == '*':
+			flushWord()
+			buf = append(buf, s.mustReadByte())
+			s.skipBlockComment(&buf)
+			continue
+
+		case b == '$' && (isDollarTagStartByte(s.peekByte()) || s.peekByte() == '$'):
+			flushWord()
+			s.skipDollarQuoted(&buf)
+
+		case b == '(':
+			flushWord()
+			parenDepth++
+
+		case b == ')':
+			flushWord()
+			if parenDepth > 0 {
+				parenDepth--
+			}
+
+		case isWordByte(b):
+			word = append(word, b)
+
+		default:
+			flushWord()
+		}
+
+		if parenDepth == 0 && blockDepth == 0 && hasSuffixDelimiter(buf, s.delimiter) {
+			full := string(buf)
+			body := strings.TrimSuffix(full, s.delimiter)
+			return &Statement{Text: full, Items: s.lex(body, opts), Line: startLine, Column: startCol}, true
+		}
+	}
+}
+
+// skipQuoted consumes a '...'/"..."/`...` literal, honoring backslash and
+// doubled-quote escaping, appending everything it reads to buf.
+func (s *splitState) skipQuoted(buf *[]byte, quote byte) {
+	for {
+		b, ok := s.readByte()
+		if !ok {
+			return
+		}
+		*buf = append(*buf, b)
+
+		if b == '\\' {
+			if nb, ok := s.readByte(); ok {
+				*buf = append(*buf, nb)
+			}
+			continue
+		}
+
+		if b == quote {
+			if s.peekByte() == quote {
+				*buf = append(*buf, s.mustReadByte())
+				continue
+			}
+			return
+		}
+	}
+}
+
+// skipDollarQuoted consumes a PostgreSQL dollar-quoted string, `$tag$ ...
+// $tag$` (tag may be empty, as in `$$ ... $$`), appending everything it
+// reads to buf. The opening '$' has already been appended by the caller;
+// no escaping applies inside a dollar-quoted body, so this only has to
+// watch for the matching closer.
+func (s *splitState) skipDollarQuoted(buf *[]byte) {
+	var tag []byte
+	for {
+		b := s.peekByte()
+		if b == '$' {
+			*buf = append(*buf, s.mustReadByte())
+			break
+		}
+		if !isWordByte(b) {
+			// Not actually a dollar-quoted string (e.g. "$abc" with no '$'
+			// closing its tag); leave it as ordinary text.
+			return
+		}
+		tag = append(tag, b)
+		*buf = append(*buf, s.mustReadByte())
+	}
+
+	closer := "$" + string(tag) + "$"
+	for {
+		b, ok := s.readByte()
+		if !ok {
+			return
+		}
+		*buf = append(*buf, b)
+		if b == '$' && hasSuffixDelimiter(*buf, closer) {
+			return
+		}
+	}
+}
+
+// skipLineComment consumes up to (but not including) the next newline or
+// EOF, appending everything it reads to buf.
+func (s *splitState) skipLineComment(buf *[]byte) {
+	for {
+		b := s.peekByte()
+		if b == 0 || b == '\n' {
+			return
+		}
+		*buf = append(*buf, s.mustReadByte())
+	}
+}
+
+// skipBlockComment consumes up to and including the closing */, appending
+// everything it reads to buf.
+func (s *splitState) skipBlockComment(buf *[]byte) {
+	for {
+		b, ok := s.readByte()
+		if !ok {
+			return
+		}
+		*buf = append(*buf, b)
+		if b == '*' && s.peekByte() == '/' {
+			*buf = append(*buf, s.mustReadByte())
+			return
+		}
+	}
+}
+
+// lex tokenizes an isolated statement body (the delimiter already
+// stripped) with the real lexer, collecting every Item through EOF.
+func (s *splitState) lex(text string, opts []lexer.Option) []lexer.Item {
+	l := lexer.Lex(strings.NewReader(text), opts...)
+
+	var items []lexer.Item
+	for {
+		item := l.NextItem()
+		items = append(items, item)
+		if item.Type == lexer.ItemEOF || item.Type == lexer.ItemError {
+			break
+		}
+	}
+	return items
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
+
+// isDollarTagStartByte reports whether b can start a dollar-quote tag, as
+// opposed to a PostgreSQL numbered parameter like $1: a letter or
+// underscore, not a digit.
+func isDollarTagStartByte(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isSpaceByte(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n'
+}
+
+func hasSuffixDelimiter(buf []byte, delim string) bool {
+	if delim == "" || len(buf) < len(delim) {
+		return false
+	}
+	return string(buf[len(buf)-len(delim):]) == delim
+}
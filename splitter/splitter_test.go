@@ -0,0 +1,98 @@
+package splitter
+
+import (
+	"strings"
+	"testing"
+)
+
+func collectTexts(input string) []string {
+	var stmts []string
+	for stmt := range SplitStatements(strings.NewReader(input)) {
+		stmts = append(stmts, stmt.Text)
+	}
+	return stmts
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "simple statements",
+			input: "SELECT 1;\nSELECT 2;\n",
+			want:  []string{"SELECT 1;", "SELECT 2;"},
+		},
+		{
+			name:  "trailing statement without a terminator",
+			input: "SELECT 1;\nSELECT 2",
+			want:  []string{"SELECT 1;", "SELECT 2"},
+		},
+		{
+			name:  "delimiter inside a string literal is ignored",
+			input: "SELECT ';';\nSELECT 2;\n",
+			want:  []string{"SELECT ';';", "SELECT 2;"},
+		},
+		{
+			name:  "delimiter inside a line comment is ignored",
+			input: "SELECT 1 -- trailing comment;\nFROM foo;\n",
+			want:  []string{"SELECT 1 -- trailing comment;\nFROM foo;"},
+		},
+		{
+			name:  "delimiter inside a block comment is ignored",
+			input: "SELECT /* a; b */ 1;\n",
+			want:  []string{"SELECT /* a; b */ 1;"},
+		},
+		{
+			name:  "delimiter inside parens is ignored",
+			input: "SELECT (1; 2);\n",
+			want:  []string{"SELECT (1; 2);"},
+		},
+		{
+			name:  "nested BEGIN/END blocks don't split early",
+			input: "CREATE PROCEDURE p() BEGIN BEGIN SELECT 1; END; SELECT 2; END;\n",
+			want:  []string{"CREATE PROCEDURE p() BEGIN BEGIN SELECT 1; END; SELECT 2; END;"},
+		},
+		{
+			name:  "an IF/END IF nested in the outer BEGIN/END doesn't close it early",
+			input: "CREATE PROCEDURE p() BEGIN IF x THEN SELECT 1; END IF; SELECT 2; END;\n",
+			want:  []string{"CREATE PROCEDURE p() BEGIN IF x THEN SELECT 1; END IF; SELECT 2; END;"},
+		},
+		{
+			name:  "a WHILE/END WHILE nested in the outer BEGIN/END doesn't close it early",
+			input: "CREATE PROCEDURE p() BEGIN WHILE x DO SELECT 1; END WHILE; SELECT 2; END;\n",
+			want:  []string{"CREATE PROCEDURE p() BEGIN WHILE x DO SELECT 1; END WHILE; SELECT 2; END;"},
+		},
+		{
+			name:  "DELIMITER directive changes the terminator",
+			input: "DELIMITER //\nSELECT 1//\nDELIMITER ;\nSELECT 2;\n",
+			want:  []string{"SELECT 1//", "SELECT 2;"},
+		},
+		{
+			name:  "dollar-quoted function body survives embedded semicolons",
+			input: "CREATE FUNCTION foo() RETURNS int AS $$ SELECT 1; SELECT 2; $$ LANGUAGE sql;\n",
+			want:  []string{"CREATE FUNCTION foo() RETURNS int AS $$ SELECT 1; SELECT 2; $$ LANGUAGE sql;"},
+		},
+		{
+			name:  "a numbered parameter is not mistaken for a dollar-quoted string",
+			input: "SELECT $1, $2;\n",
+			want:  []string{"SELECT $1, $2;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectTexts(tt.input)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d statements %q, want %d %q", len(got), got, len(tt.want), tt.want)
+			}
+			for i, stmt := range got {
+				if stmt != tt.want[i] {
+					t.Errorf("statement %d = %q, want %q", i, stmt, tt.want[i])
+				}
+			}
+		})
+	}
+}